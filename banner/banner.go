@@ -0,0 +1,168 @@
+// Package banner wraps the Banner Student Registration session lifecycle
+// (classSearch -> term selection -> getEnrollmentInfo) behind a typed
+// Client, instead of scraping the HTML with a one-off regex tied to a
+// single hardcoded term.
+package banner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	classSearchURL    = "https://nubanner.neu.edu/StudentRegistrationSsb/ssb/classSearch/classSearch"
+	termSearchURL     = "https://nubanner.neu.edu/StudentRegistrationSsb/ssb/term/search?mode=search"
+	enrollmentInfoURL = "https://nubanner.neu.edu/StudentRegistrationSsb/ssb/searchResults/getEnrollmentInfo"
+)
+
+// EnrollmentInfo is the subset of Banner's per-CRN enrollment page this
+// client extracts.
+type EnrollmentInfo struct {
+	Seats             int
+	WaitlistCapacity  int
+	WaitlistAvailable int
+	CrossListSeats    int
+}
+
+// HasOpenSeat reports whether a subscriber watching this CRN should be
+// notified.
+func (e EnrollmentInfo) HasOpenSeat() bool {
+	return e.Seats > 0
+}
+
+// Client manages one Banner browsing session (its JSESSIONID and
+// nubanner-cookie, tracked via a cookie jar) and re-establishes it
+// automatically if Banner ever redirects a request back to its login page.
+type Client struct {
+	// Term is the Banner term code (e.g. "202430") every request is scoped
+	// to. It's a field rather than a constant so callers can point the
+	// client at a different term without rebuilding it.
+	Term string
+
+	httpClient *http.Client
+
+	mu           sync.Mutex
+	sessionReady bool
+}
+
+// NewClient builds a Client scoped to term, with its own cookie jar.
+func NewClient(term string) *Client {
+	jar, _ := cookiejar.New(nil)
+	return &Client{
+		Term:       term,
+		httpClient: &http.Client{Jar: jar, Timeout: 15 * time.Second},
+	}
+}
+
+// GetEnrollmentInfo fetches and parses the enrollment info for crn,
+// establishing a session first if needed and transparently re-establishing
+// it once if Banner has expired the current one. A single Client is shared
+// across every CRN's poller goroutine, so only session (re)establishment is
+// serialized through c.mu; the enrollment-info round trips themselves run
+// unlocked and can proceed concurrently once the session is ready.
+func (c *Client) GetEnrollmentInfo(ctx context.Context, crn string) (EnrollmentInfo, error) {
+	if err := c.ensureSession(ctx); err != nil {
+		return EnrollmentInfo{}, err
+	}
+
+	info, expired, err := c.fetchEnrollmentInfo(ctx, crn)
+	if err != nil {
+		return EnrollmentInfo{}, err
+	}
+	if !expired {
+		return info, nil
+	}
+
+	c.mu.Lock()
+	c.sessionReady = false
+	c.mu.Unlock()
+	if err := c.ensureSession(ctx); err != nil {
+		return EnrollmentInfo{}, err
+	}
+	info, _, err = c.fetchEnrollmentInfo(ctx, crn)
+	return info, err
+}
+
+// ensureSession establishes a session if one isn't ready yet, holding c.mu
+// only for the check and the (re)establishment itself.
+func (c *Client) ensureSession(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessionReady {
+		return nil
+	}
+	return c.startSession(ctx)
+}
+
+// startSession visits classSearch to pick up the session cookies and then
+// selects c.Term, the same two requests a browser makes before searching.
+func (c *Client) startSession(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, classSearchURL, nil)
+	if err != nil {
+		return fmt.Errorf("banner: building classSearch request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("banner: fetching classSearch: %w", err)
+	}
+	resp.Body.Close()
+
+	form := url.Values{}
+	form.Set("term", c.Term)
+	termReq, err := http.NewRequestWithContext(ctx, http.MethodPost, termSearchURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("banner: building term selection request: %w", err)
+	}
+	termReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	termResp, err := c.httpClient.Do(termReq)
+	if err != nil {
+		return fmt.Errorf("banner: selecting term: %w", err)
+	}
+	termResp.Body.Close()
+
+	c.sessionReady = true
+	return nil
+}
+
+// fetchEnrollmentInfo requests the enrollment info for crn. The second
+// return value reports whether Banner bounced the request back to its
+// login/selfServiceMenu page, meaning the session expired mid-flight.
+func (c *Client) fetchEnrollmentInfo(ctx context.Context, crn string) (EnrollmentInfo, bool, error) {
+	form := url.Values{}
+	form.Set("term", c.Term)
+	form.Set("courseReferenceNumber", crn)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, enrollmentInfoURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return EnrollmentInfo{}, false, fmt.Errorf("banner: building enrollment info request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return EnrollmentInfo{}, false, fmt.Errorf("banner: fetching enrollment info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if isLoginRedirect(resp) {
+		return EnrollmentInfo{}, true, nil
+	}
+
+	info, err := parseEnrollmentInfo(resp.Body)
+	if err != nil {
+		return EnrollmentInfo{}, false, err
+	}
+	return info, false, nil
+}
+
+// isLoginRedirect reports whether Banner served the self-service login page
+// instead of enrollment data, which happens once the session has expired.
+func isLoginRedirect(resp *http.Response) bool {
+	return resp.Request != nil && strings.Contains(resp.Request.URL.Path, "/selfServiceMenu/")
+}
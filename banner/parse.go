@@ -0,0 +1,70 @@
+package banner
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Banner renders each enrollment field as a label span immediately
+// followed by a `<span dir="ltr">` holding its value, e.g.:
+//
+//	<span>Enrollment Seats Available:</span> <span dir="ltr"> 3 </span>
+const (
+	labelSeats             = "Enrollment Seats Available:"
+	labelWaitlistCapacity  = "Waitlist Capacity:"
+	labelWaitlistAvailable = "Waitlist Seats Available:"
+	labelCrossListSeats    = "Cross List Seats Available:"
+)
+
+// parseEnrollmentInfo walks getEnrollmentInfo's HTML response looking for
+// each known label span and reading the integer out of the value span that
+// follows it, rather than matching the whole fragment with one regex.
+func parseEnrollmentInfo(r io.Reader) (EnrollmentInfo, error) {
+	fields := map[string]int{}
+	tokenizer := html.NewTokenizer(r)
+
+	var pendingLabel string
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.TextToken {
+			continue
+		}
+
+		text := strings.TrimSpace(tokenizer.Token().Data)
+		if text == "" {
+			continue
+		}
+
+		switch text {
+		case labelSeats, labelWaitlistCapacity, labelWaitlistAvailable, labelCrossListSeats:
+			pendingLabel = text
+			continue
+		}
+
+		if pendingLabel == "" {
+			continue
+		}
+		if value, err := strconv.Atoi(text); err == nil {
+			fields[pendingLabel] = value
+		}
+		pendingLabel = ""
+	}
+
+	if _, ok := fields[labelSeats]; !ok {
+		return EnrollmentInfo{}, errors.New("banner: could not find available seats in enrollment info")
+	}
+
+	return EnrollmentInfo{
+		Seats:             fields[labelSeats],
+		WaitlistCapacity:  fields[labelWaitlistCapacity],
+		WaitlistAvailable: fields[labelWaitlistAvailable],
+		CrossListSeats:    fields[labelCrossListSeats],
+	}, nil
+}
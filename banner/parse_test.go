@@ -0,0 +1,33 @@
+package banner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEnrollmentInfo(t *testing.T) {
+	html := `<div>
+		<span>Enrollment Seats Available:</span> <span dir="ltr"> 3 </span>
+		<span>Waitlist Capacity:</span> <span dir="ltr"> 10 </span>
+		<span>Waitlist Seats Available:</span> <span dir="ltr"> 2 </span>
+		<span>Cross List Seats Available:</span> <span dir="ltr"> 0 </span>
+	</div>`
+
+	info, err := parseEnrollmentInfo(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parseEnrollmentInfo: %v", err)
+	}
+	if info.Seats != 3 || info.WaitlistCapacity != 10 || info.WaitlistAvailable != 2 || info.CrossListSeats != 0 {
+		t.Fatalf("unexpected enrollment info: %+v", info)
+	}
+	if !info.HasOpenSeat() {
+		t.Fatal("expected HasOpenSeat to be true with 3 seats available")
+	}
+}
+
+func TestParseEnrollmentInfoMissingSeats(t *testing.T) {
+	_, err := parseEnrollmentInfo(strings.NewReader(`<div>nothing useful here</div>`))
+	if err == nil {
+		t.Fatal("expected an error when the seats field is missing")
+	}
+}
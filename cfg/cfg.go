@@ -0,0 +1,199 @@
+// Package cfg loads server configuration from an optional YAML/JSON file,
+// overlaid with environment variables, instead of the SMTP credentials and
+// other secrets previously hardcoded into main.
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultSMTPPort       = "587"
+	defaultMastodonServer = "https://mastodon.social"
+	defaultDBPath         = "nubanner.db"
+	defaultBaseURL        = "http://localhost:8080"
+	defaultTerm           = "202430"
+	defaultPollInterval   = 60 * time.Second
+)
+
+// Config is everything main needs to start the server. It's assembled by
+// Load and then threaded through to the handlers, poller, and notifier
+// backends instead of each reading its own hardcoded literals.
+type Config struct {
+	SMTPHost string
+	SMTPPort string
+	SMTPUser string
+	SMTPPass string
+	SMTPFrom string
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFromNumber string
+
+	MastodonServer       string
+	MastodonClientID     string
+	MastodonClientSecret string
+	MastodonAccessToken  string
+
+	DBPath  string
+	BaseURL string
+	Term    string
+
+	PollInterval time.Duration
+
+	HMACSecret string
+}
+
+// fileConfig mirrors Config as it appears on disk. PollInterval is a string
+// here (e.g. "60s") since that's how a duration is written in YAML/JSON; it
+// gets parsed into Config.PollInterval once overrides are applied.
+type fileConfig struct {
+	SMTPHost string `yaml:"smtp_host" json:"smtp_host"`
+	SMTPPort string `yaml:"smtp_port" json:"smtp_port"`
+	SMTPUser string `yaml:"smtp_user" json:"smtp_user"`
+	SMTPPass string `yaml:"smtp_pass" json:"smtp_pass"`
+	SMTPFrom string `yaml:"smtp_from" json:"smtp_from"`
+
+	TwilioAccountSID string `yaml:"twilio_account_sid" json:"twilio_account_sid"`
+	TwilioAuthToken  string `yaml:"twilio_auth_token" json:"twilio_auth_token"`
+	TwilioFromNumber string `yaml:"twilio_from_number" json:"twilio_from_number"`
+
+	MastodonServer       string `yaml:"mastodon_server" json:"mastodon_server"`
+	MastodonClientID     string `yaml:"mastodon_client_id" json:"mastodon_client_id"`
+	MastodonClientSecret string `yaml:"mastodon_client_secret" json:"mastodon_client_secret"`
+	MastodonAccessToken  string `yaml:"mastodon_access_token" json:"mastodon_access_token"`
+
+	DBPath  string `yaml:"db_path" json:"db_path"`
+	BaseURL string `yaml:"base_url" json:"base_url"`
+	Term    string `yaml:"term" json:"term"`
+
+	PollInterval string `yaml:"poll_interval" json:"poll_interval"`
+
+	HMACSecret string `yaml:"hmac_secret" json:"hmac_secret"`
+}
+
+// Load reads path (if it exists) as YAML, or JSON when path ends in
+// ".json", layers the NUBANNER_* environment variables on top, fills in
+// defaults for anything still unset, and validates that what's left is
+// enough to start the server. path may be empty, in which case only
+// environment variables and defaults apply.
+func Load(path string) (*Config, error) {
+	fc := fileConfig{
+		SMTPPort:       defaultSMTPPort,
+		MastodonServer: defaultMastodonServer,
+		DBPath:         defaultDBPath,
+		BaseURL:        defaultBaseURL,
+		Term:           defaultTerm,
+		PollInterval:   defaultPollInterval.String(),
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := unmarshal(path, data, &fc); err != nil {
+				return nil, err
+			}
+		case os.IsNotExist(err):
+			// No config file on disk; environment variables and defaults
+			// carry the whole configuration.
+		default:
+			return nil, fmt.Errorf("cfg: reading %s: %w", path, err)
+		}
+	}
+
+	applyEnvOverrides(&fc)
+
+	pollInterval, err := time.ParseDuration(fc.PollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("cfg: invalid poll_interval %q: %w", fc.PollInterval, err)
+	}
+
+	c := &Config{
+		SMTPHost: fc.SMTPHost,
+		SMTPPort: fc.SMTPPort,
+		SMTPUser: fc.SMTPUser,
+		SMTPPass: fc.SMTPPass,
+		SMTPFrom: fc.SMTPFrom,
+
+		TwilioAccountSID: fc.TwilioAccountSID,
+		TwilioAuthToken:  fc.TwilioAuthToken,
+		TwilioFromNumber: fc.TwilioFromNumber,
+
+		MastodonServer:       fc.MastodonServer,
+		MastodonClientID:     fc.MastodonClientID,
+		MastodonClientSecret: fc.MastodonClientSecret,
+		MastodonAccessToken:  fc.MastodonAccessToken,
+
+		DBPath:  fc.DBPath,
+		BaseURL: fc.BaseURL,
+		Term:    fc.Term,
+
+		PollInterval: pollInterval,
+
+		HMACSecret: fc.HMACSecret,
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func unmarshal(path string, data []byte, fc *fileConfig) error {
+	if strings.HasSuffix(path, ".json") {
+		if err := json.Unmarshal(data, fc); err != nil {
+			return fmt.Errorf("cfg: parsing %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := yaml.Unmarshal(data, fc); err != nil {
+		return fmt.Errorf("cfg: parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnvOverrides(fc *fileConfig) {
+	overrideString(&fc.SMTPHost, "NUBANNER_SMTP_HOST")
+	overrideString(&fc.SMTPUser, "NUBANNER_SMTP_USER")
+	overrideString(&fc.SMTPPass, "NUBANNER_SMTP_PASS")
+	overrideString(&fc.SMTPFrom, "NUBANNER_SMTP_FROM")
+	overrideString(&fc.PollInterval, "NUBANNER_POLL_INTERVAL")
+	overrideString(&fc.Term, "NUBANNER_TERM")
+	overrideString(&fc.HMACSecret, "NUBANNER_HMAC_SECRET")
+}
+
+func overrideString(dst *string, envVar string) {
+	if v, ok := os.LookupEnv(envVar); ok {
+		*dst = v
+	}
+}
+
+// validate checks that required fields are set. The HMAC secret is always
+// required since there's no safe default for it. SMTP credentials are only
+// required once an SMTP host is configured; leaving SMTPHost empty is how
+// main selects the NullMailer for tests and dry runs instead of failing.
+func (c *Config) validate() error {
+	var missing []string
+	if c.HMACSecret == "" {
+		missing = append(missing, "NUBANNER_HMAC_SECRET")
+	}
+	if c.SMTPHost != "" {
+		if c.SMTPUser == "" {
+			missing = append(missing, "NUBANNER_SMTP_USER")
+		}
+		if c.SMTPPass == "" {
+			missing = append(missing, "NUBANNER_SMTP_PASS")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cfg: missing required configuration: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadAppliesDefaultsAndEnvOverrides(t *testing.T) {
+	t.Setenv("NUBANNER_HMAC_SECRET", "test-secret")
+	t.Setenv("NUBANNER_TERM", "202510")
+
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Term != "202510" {
+		t.Errorf("Term = %q, want env override 202510", c.Term)
+	}
+	if c.DBPath != defaultDBPath {
+		t.Errorf("DBPath = %q, want default %q", c.DBPath, defaultDBPath)
+	}
+	if c.PollInterval != defaultPollInterval {
+		t.Errorf("PollInterval = %v, want default %v", c.PollInterval, defaultPollInterval)
+	}
+}
+
+func TestLoadReadsYAMLFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nubanner.yaml")
+	contents := "hmac_secret: from-file\nsmtp_host: smtp.example.com\nsmtp_user: bot\nsmtp_pass: hunter2\npoll_interval: 90s\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.HMACSecret != "from-file" {
+		t.Errorf("HMACSecret = %q, want from-file", c.HMACSecret)
+	}
+	if c.PollInterval != 90*time.Second {
+		t.Errorf("PollInterval = %v, want 90s", c.PollInterval)
+	}
+}
+
+func TestLoadMissingHMACSecretFails(t *testing.T) {
+	if _, err := Load(""); err == nil {
+		t.Fatal("expected an error when NUBANNER_HMAC_SECRET is unset")
+	}
+}
+
+func TestLoadRequiresSMTPCredentialsWhenHostSet(t *testing.T) {
+	t.Setenv("NUBANNER_HMAC_SECRET", "test-secret")
+	t.Setenv("NUBANNER_SMTP_HOST", "smtp.example.com")
+
+	if _, err := Load(""); err == nil {
+		t.Fatal("expected an error when SMTP host is set without user/pass")
+	}
+}
@@ -1,37 +1,119 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"github.com/gin-gonic/gin"
-	"io/ioutil"
+	"fmt"
 	"log"
 	"net/http"
-	"net/smtp"
-	"net/url"
-	"regexp"
-	"strconv"
-	"strings"
-	"sync"
+	"os"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Jianzhong-Liu/NuBanner/banner"
+	"github.com/Jianzhong-Liu/NuBanner/cfg"
+	"github.com/Jianzhong-Liu/NuBanner/notifier"
+	"github.com/Jianzhong-Liu/NuBanner/poller"
+	"github.com/Jianzhong-Liu/NuBanner/store"
+	"github.com/Jianzhong-Liu/NuBanner/token"
 )
 
+// configPath is where Load looks for the optional YAML/JSON config file,
+// overridable so a deployment can point at a different path without a
+// rebuild.
+const configPath = "nubanner.yaml"
+
+const tokenTTL = 24 * time.Hour
+
+// rawMailer is the subset of Notifier the confirmation email flow needs
+// beyond seat-event delivery: sending an arbitrary subject/body outside of
+// a SeatEvent. Both SMTPNotifier and NullMailer implement it.
+type rawMailer interface {
+	notifier.Notifier
+	SendRaw(ctx context.Context, to, subject, body string) error
+}
+
 var (
-	subscriptions    = make(map[string]chan bool)
-	subscriptionsMux sync.Mutex
+	db              store.Store
+	p               *poller.Poller
+	conf            *cfg.Config
+	hmacSecretBytes []byte
+	mailer          rawMailer
 )
 
 func main() {
+	path := configPath
+	if v := os.Getenv("NUBANNER_CONFIG"); v != "" {
+		path = v
+	}
+	c, err := cfg.Load(path)
+	if err != nil {
+		log.Fatal("Failed to load config: ", err)
+	}
+	conf = c
+	hmacSecretBytes = []byte(conf.HMACSecret)
+	mailer = newMailer(conf)
+
+	s, err := store.NewSQLiteStore(conf.DBPath)
+	if err != nil {
+		log.Fatal("Failed to open store: ", err)
+	}
+	db = s
+
+	p = poller.New(db, banner.NewClient(conf.Term), conf.PollInterval)
+	p.LinkFunc = unsubscribeLink
+
+	if err := restoreSubscriptions(); err != nil {
+		log.Fatal("Failed to restore subscriptions: ", err)
+	}
+
 	router := gin.Default()
 
 	router.POST("/start-course-check", startCourseCheckHandler)
-	router.POST("/stop-course-check", stopCourseCheckHandler)
+	router.GET("/confirm", confirmHandler)
+	router.GET("/unsubscribe", unsubscribeHandler)
+	router.GET("/subscriptions", listSubscriptionsHandler)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	err := router.Run(":8080")
-	if err != nil {
+	if err := router.Run(":8080"); err != nil {
 		log.Fatal("Failed to run server: ", err)
 	}
 }
 
+// newMailer picks the email backend: a real SMTPNotifier once an SMTP host
+// is configured, otherwise a NullMailer that logs instead of sending, which
+// is what lets tests and dry runs work without SMTP credentials.
+func newMailer(c *cfg.Config) rawMailer {
+	if c.SMTPHost == "" {
+		return notifier.NewNullMailer()
+	}
+	return notifier.NewSMTPNotifier(c.SMTPHost, c.SMTPPort, c.SMTPUser, c.SMTPFrom, c.SMTPPass)
+}
+
+// restoreSubscriptions loads every confirmed subscription and hands it back
+// to the poller, so a restart doesn't silently drop active checks.
+func restoreSubscriptions() error {
+	subs, err := db.List()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		backends, err := notifiersFromConfigs(sub.Channels)
+		if err != nil {
+			log.Println("Skipping subscription with invalid channels:", sub.Email, sub.CRN, err)
+			continue
+		}
+		p.Watch(sub, notifier.NewMultiNotifier(backends...))
+	}
+	return nil
+}
+
+// startCourseCheckHandler enqueues a pending subscription and emails the
+// subscriber a signed confirmation link. The check itself doesn't start
+// until /confirm is hit, so posting someone else's email just gets them a
+// link they can ignore.
 func startCourseCheckHandler(c *gin.Context) {
 	email := c.Query("email")
 	crn := c.Query("CRN")
@@ -40,116 +122,230 @@ func startCourseCheckHandler(c *gin.Context) {
 		return
 	}
 
-	subscriptionsMux.Lock()
-	if _, exists := subscriptions[email]; exists {
-		subscriptionsMux.Unlock()
-		c.JSON(http.StatusBadRequest, gin.H{"error": "A check is already running for this email"})
+	channels := c.QueryArray("channel")
+	if len(channels) == 0 {
+		channels = []string{"email"}
+	}
+
+	configs, err := channelConfigsFromRequest(c, channels)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := notifiersFromConfigs(configs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	stopChan := make(chan bool)
-	subscriptions[email] = stopChan
-	subscriptionsMux.Unlock()
+	sub := store.Subscription{Email: email, CRN: crn, Channels: configs, CreatedAt: time.Now()}
+	if err := db.AddPending(sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A check is already pending or running for this email/CRN"})
+		return
+	}
 
-	go checkCourseAvailability(email, crn, stopChan)
-	c.JSON(http.StatusOK, gin.H{"message": "Course availability check started for " + email})
+	confirmToken, err := token.Generate(hmacSecretBytes, email, crn, token.PurposeConfirm, tokenTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate confirmation token"})
+		return
+	}
+	link := fmt.Sprintf("%s/confirm?token=%s", conf.BaseURL, confirmToken)
+	if err := sendConfirmationEmail(email, link); err != nil {
+		log.Println("Error sending confirmation email: ", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Confirmation email sent to " + email})
 }
 
-func stopCourseCheckHandler(c *gin.Context) {
-	email := c.Query("email")
-	if email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+// confirmHandler activates a pending subscription and hands it to the
+// poller once the signed, single-use confirmation token checks out.
+func confirmHandler(c *gin.Context) {
+	raw := c.Query("token")
+	claims, err := validateToken(raw, token.PurposeConfirm)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Confirm(claims.Email, claims.CRN); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No pending subscription for this link"})
+		return
+	}
+	if err := db.MarkTokenUsed(claims.Nonce); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record token use"})
 		return
 	}
 
-	subscriptionsMux.Lock()
-	if stopChan, exists := subscriptions[email]; exists {
-		stopChan <- true
-		close(stopChan)
-		delete(subscriptions, email)
-		subscriptionsMux.Unlock()
-		c.JSON(http.StatusOK, gin.H{"message": "Course check stopped for " + email})
+	subs, err := db.ListByEmail(claims.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load confirmed subscription"})
 		return
 	}
-	subscriptionsMux.Unlock()
+	for _, sub := range subs {
+		if sub.CRN != claims.CRN {
+			continue
+		}
+		backends, err := notifiersFromConfigs(sub.Channels)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start check"})
+			return
+		}
+		p.Watch(sub, notifier.NewMultiNotifier(backends...))
+		break
+	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "No active check found for this email"})
+	c.JSON(http.StatusOK, gin.H{"message": "Course availability check confirmed for " + claims.Email})
 }
 
-func checkCourseAvailability(email string, crn string, stopChan chan bool) {
-	ticker := time.NewTicker(60 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			apiUrl := "https://nubanner.neu.edu/StudentRegistrationSsb/ssb/searchResults/getEnrollmentInfo"
-			form := url.Values{}
-			form.Add("term", "202430")
-			form.Add("courseReferenceNumber", crn)
-
-			req, err := http.NewRequest("POST", apiUrl, strings.NewReader(form.Encode()))
-			if err != nil {
-				log.Println("Error creating request: ", err)
-				return
-			}
-			req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+// unsubscribeHandler removes a subscription (active or pending) once the
+// signed, single-use unsubscribe token checks out.
+func unsubscribeHandler(c *gin.Context) {
+	raw := c.Query("token")
+	claims, err := validateToken(raw, token.PurposeUnsubscribe)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-			client := &http.Client{}
-			resp, err := client.Do(req)
-			if err != nil {
-				log.Println("Error making request: ", err)
-				return
-			}
-			defer resp.Body.Close()
+	if err := db.MarkTokenUsed(claims.Nonce); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record token use"})
+		return
+	}
+	p.Unwatch(claims.Email, claims.CRN)
+	if err := db.Remove(claims.Email, claims.CRN); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove subscription"})
+		return
+	}
 
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				log.Println("Error reading response body: ", err)
-				return
-			}
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed " + claims.Email + " from " + claims.CRN})
+}
 
-			availableSeats, err := parseAvailableSeats(string(body))
-			if err != nil {
-				log.Println("Error parsing available seats: ", err)
-				return
-			}
+// validateToken parses and verifies raw, checking its purpose matches want
+// and that its nonce hasn't already been redeemed.
+func validateToken(raw string, want token.Purpose) (token.Claims, error) {
+	if raw == "" {
+		return token.Claims{}, errors.New("token is required")
+	}
+	claims, err := token.Parse(hmacSecretBytes, raw)
+	if err != nil {
+		return token.Claims{}, err
+	}
+	if claims.Purpose != want {
+		return token.Claims{}, errors.New("token: wrong purpose")
+	}
+	used, err := db.IsTokenUsed(claims.Nonce)
+	if err != nil {
+		return token.Claims{}, err
+	}
+	if used {
+		return token.Claims{}, errors.New("token: already used")
+	}
+	return claims, nil
+}
+
+// sendConfirmationEmail emails a one-click confirmation link through the
+// configured mailer (SMTP, or the logging NullMailer when no SMTP host is
+// set), independent of whichever channels the subscriber requested for
+// actual seat notifications.
+func sendConfirmationEmail(email, link string) error {
+	body := "Confirm your NuBanner course check by visiting: " + link + "\r\n\r\nThis link expires in 24 hours."
+	return mailer.SendRaw(context.Background(), email, "Confirm your course availability check", body)
+}
 
-			if availableSeats > 0 {
-				sendEmailNotification(email, availableSeats, crn)
+// channelConfigsFromRequest turns the requested channel names (plus their
+// per-channel destination query params) into persistable ChannelConfigs.
+func channelConfigsFromRequest(c *gin.Context, channels []string) ([]store.ChannelConfig, error) {
+	var configs []store.ChannelConfig
+	for _, ch := range channels {
+		switch ch {
+		case "email":
+			configs = append(configs, store.ChannelConfig{Type: "email"})
+		case "webhook":
+			webhookURL := c.Query("webhookURL")
+			if webhookURL == "" {
+				return nil, errors.New("webhookURL is required for the webhook channel")
 			}
-		case <-stopChan:
-			log.Println("Stopping course check for", email)
-			return
+			configs = append(configs, store.ChannelConfig{Type: "webhook", Target: webhookURL})
+		case "sms":
+			phone := c.Query("phone")
+			if phone == "" {
+				return nil, errors.New("phone is required for the sms channel")
+			}
+			configs = append(configs, store.ChannelConfig{Type: "sms", Target: phone})
+		case "mastodon":
+			handle := c.Query("mastodonHandle")
+			if handle == "" {
+				return nil, errors.New("mastodonHandle is required for the mastodon channel")
+			}
+			configs = append(configs, store.ChannelConfig{Type: "mastodon", Target: handle})
+		default:
+			return nil, errors.New("unknown channel: " + ch)
 		}
 	}
+	return configs, nil
 }
 
-func parseAvailableSeats(htmlStr string) (int, error) {
-	re := regexp.MustCompile(`Enrollment Seats Available:</span> <span dir="ltr"> (-?\d+) </span>`)
-	matches := re.FindStringSubmatch(htmlStr)
-	if len(matches) < 2 {
-		return 0, errors.New("could not find available seats in HTML")
+// notifiersFromConfigs builds the concrete Notifier backends for a
+// subscription's persisted channel configs.
+func notifiersFromConfigs(configs []store.ChannelConfig) ([]notifier.Notifier, error) {
+	var backends []notifier.Notifier
+	for _, chCfg := range configs {
+		switch chCfg.Type {
+		case "email":
+			backends = append(backends, mailer)
+		case "webhook":
+			backends = append(backends, notifier.NewWebhookNotifier(chCfg.Target))
+		case "sms":
+			backends = append(backends, notifier.NewSMSNotifier(conf.TwilioAccountSID, conf.TwilioAuthToken, conf.TwilioFromNumber, chCfg.Target))
+		case "mastodon":
+			backends = append(backends, notifier.NewMastodonNotifier(conf.MastodonServer, conf.MastodonClientID, conf.MastodonClientSecret, conf.MastodonAccessToken, chCfg.Target))
+		default:
+			return nil, errors.New("unknown channel: " + chCfg.Type)
+		}
 	}
-
-	return strconv.Atoi(matches[1])
+	return backends, nil
 }
 
-func sendEmailNotification(email string, availableSeats int, crn string) {
-	from := "jianznucheck@gmail.com"
-	password := "kpuqoqjynyiplkqh"
-	to := []string{email}
+// listSubscriptionsHandler returns one user's active watchlist, including
+// the seat count observed on each CRN's last poll. It reports only each
+// channel's Type, not its Target (phone number, webhook URL, Mastodon
+// handle, ...): this endpoint takes an email with no proof of ownership, so
+// it must not hand back the PII a Target carries.
+func listSubscriptionsHandler(c *gin.Context) {
+	email := c.Query("email")
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
 
-	smtpHost := "smtp.gmail.com"
-	smtpPort := "587"
+	subs, err := db.ListByEmail(email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load watchlist"})
+		return
+	}
 
-	message := []byte("Subject: Course Slot Available\r\n\r\nA slot is available. There are " + strconv.Itoa(availableSeats) + " seats available for you subscribe course: " + crn)
+	watchlist := make([]gin.H, 0, len(subs))
+	for _, sub := range subs {
+		channelTypes := make([]string, 0, len(sub.Channels))
+		for _, ch := range sub.Channels {
+			channelTypes = append(channelTypes, ch.Type)
+		}
+		watchlist = append(watchlist, gin.H{
+			"crn":          sub.CRN,
+			"channels":     channelTypes,
+			"lastSeats":    sub.LastSeats,
+			"lastPolledAt": sub.LastPolledAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"email": email, "subscriptions": watchlist})
+}
 
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, message)
+// unsubscribeLink mints a fresh signed unsubscribe token for every
+// notification, since tokens are single-use.
+func unsubscribeLink(email, crn string) string {
+	t, err := token.Generate(hmacSecretBytes, email, crn, token.PurposeUnsubscribe, tokenTTL)
 	if err != nil {
-		log.Println("Error sending email: ", err)
-	} else {
-		log.Println("Send Notification to", email, "Successfully")
+		log.Println("Error generating unsubscribe token: ", err)
+		return ""
 	}
+	return fmt.Sprintf("%s/unsubscribe?token=%s", conf.BaseURL, t)
 }
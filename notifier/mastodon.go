@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mattn/go-mastodon"
+)
+
+// MastodonNotifier delivers a seat-availability alert as a direct message
+// (a status visible only to the mentioned account) on a Mastodon instance.
+type MastodonNotifier struct {
+	client  *mastodon.Client
+	handle  string // e.g. "@user@mastodon.social"
+	limiter *perChannelLimiter
+}
+
+// NewMastodonNotifier builds a MastodonNotifier that DMs handle from the
+// account authenticated by the given app/instance credentials. Its rate
+// limit is shared across every MastodonNotifier posting from that same
+// account, since they all count against one instance-side quota.
+func NewMastodonNotifier(server, clientID, clientSecret, accessToken, handle string) *MastodonNotifier {
+	client := mastodon.NewClient(&mastodon.Config{
+		Server:       server,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AccessToken:  accessToken,
+	})
+	return &MastodonNotifier{
+		client:  client,
+		handle:  handle,
+		limiter: sharedLimiter("mastodon:"+server+":"+accessToken, 1, 3),
+	}
+}
+
+func (m *MastodonNotifier) Channel() string { return "mastodon" }
+
+func (m *MastodonNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	if err := m.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	status := fmt.Sprintf("%s A slot is available. There are %d seats available for you subscribe course: %s",
+		m.handle, event.AvailableSeats, event.CRN)
+	if event.UnsubscribeLink != "" {
+		status += " Unsubscribe: " + event.UnsubscribeLink
+	}
+
+	return withRetry(ctx, defaultRetry, func() error {
+		_, err := m.client.PostStatus(ctx, &mastodon.Toot{
+			Status:     status,
+			Visibility: "direct",
+		})
+		return err
+	})
+}
+
+var _ Notifier = (*MastodonNotifier)(nil)
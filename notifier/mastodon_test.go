@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMastodonNotifierPostsDirectStatus(t *testing.T) {
+	var gotAuth string
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	m := NewMastodonNotifier(srv.URL, "client-id", "client-secret", "access-token", "@user@mastodon.social")
+	err := m.Notify(context.Background(), SeatEvent{
+		Email:           "a@example.com",
+		CRN:             "12345",
+		AvailableSeats:  4,
+		UnsubscribeLink: "https://example.com/unsubscribe?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if gotAuth != "Bearer access-token" {
+		t.Fatalf("Authorization = %q, want Bearer access-token", gotAuth)
+	}
+
+	form, err := url.ParseQuery(gotBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	status := form.Get("status")
+	if !strings.Contains(status, "@user@mastodon.social") {
+		t.Fatalf("status = %q, want it to mention the handle", status)
+	}
+	if !strings.Contains(status, "12345") {
+		t.Fatalf("status = %q, want it to mention the CRN", status)
+	}
+	if !strings.Contains(status, "https://example.com/unsubscribe?token=abc") {
+		t.Fatalf("status = %q, want it to include the unsubscribe link", status)
+	}
+}
+
+func TestMastodonNotifierRetriesOnFailureThenSucceeds(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	m := NewMastodonNotifier(srv.URL, "client-id", "client-secret", "access-token-2", "@user@mastodon.social")
+	if err := m.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345", AvailableSeats: 1}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("server called %d times, want 2 (one failure then a retry that succeeds)", calls)
+	}
+}
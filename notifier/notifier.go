@@ -0,0 +1,92 @@
+// Package notifier defines the pluggable notification backends used to tell
+// a user a seat opened up. Each backend implements Notifier and is selected
+// per-subscription via the "channel" query parameter on /start-course-check.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SeatEvent describes a single seat-availability change a Notifier should
+// deliver to a subscriber.
+type SeatEvent struct {
+	Email          string
+	CRN            string
+	AvailableSeats int
+	// UnsubscribeLink, if set, is appended to the delivered message so a
+	// recipient can opt out without knowing the API.
+	UnsubscribeLink string
+}
+
+// Notifier delivers a SeatEvent over some channel (email, webhook, SMS, ...).
+type Notifier interface {
+	// Channel returns the name used to select this backend, e.g. "email".
+	Channel() string
+	Notify(ctx context.Context, event SeatEvent) error
+}
+
+// retryConfig bounds how many times a backend will retry a failed send and
+// how long it waits between attempts.
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+var defaultRetry = retryConfig{maxAttempts: 3, baseDelay: 500 * time.Millisecond}
+
+// defaultHTTPTimeout bounds how long the SMS and webhook backends wait on an
+// outbound HTTP call before giving up and letting withRetry decide whether to
+// retry. The Mastodon backend's client is not built with it.
+const defaultHTTPTimeout = 10 * time.Second
+
+// withRetry runs send, retrying with linear backoff until it succeeds or
+// maxAttempts is reached. The context is honored between attempts.
+func withRetry(ctx context.Context, cfg retryConfig, send func() error) error {
+	var lastErr error
+	for attempt := 1; attempt <= cfg.maxAttempts; attempt++ {
+		if err := send(); err != nil {
+			lastErr = err
+			if attempt == cfg.maxAttempts {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cfg.baseDelay * time.Duration(attempt)):
+			}
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("notifier: all %d attempts failed: %w", cfg.maxAttempts, lastErr)
+}
+
+// MultiNotifier fans a SeatEvent out to every configured backend and
+// aggregates any errors rather than failing fast.
+type MultiNotifier struct {
+	backends []Notifier
+}
+
+// NewMultiNotifier builds a MultiNotifier over the given backends.
+func NewMultiNotifier(backends ...Notifier) *MultiNotifier {
+	return &MultiNotifier{backends: backends}
+}
+
+func (m *MultiNotifier) Channel() string { return "multi" }
+
+func (m *MultiNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	var errs []error
+	for _, b := range m.backends {
+		if err := b.Notify(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Channel(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notifier: %d/%d backends failed: %v", len(errs), len(m.backends), errs)
+	}
+	return nil
+}
+
+var _ Notifier = (*MultiNotifier)(nil)
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeNotifier struct {
+	channel string
+	err     error
+	calls   int
+}
+
+func (f *fakeNotifier) Channel() string { return f.channel }
+
+func (f *fakeNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	f.calls++
+	return f.err
+}
+
+func TestMultiNotifierFanOut(t *testing.T) {
+	var m Notifier = NewMultiNotifier(&fakeNotifier{channel: "email"}, &fakeNotifier{channel: "sms"})
+	if m.Channel() != "multi" {
+		t.Fatalf("Channel() = %q, want multi", m.Channel())
+	}
+
+	a := &fakeNotifier{channel: "email"}
+	b := &fakeNotifier{channel: "sms"}
+	multi := NewMultiNotifier(a, b)
+
+	if err := multi.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if a.calls != 1 || b.calls != 1 {
+		t.Fatalf("expected both backends notified once, got a=%d b=%d", a.calls, b.calls)
+	}
+}
+
+func TestSharedLimiterIsReusedPerKey(t *testing.T) {
+	a := sharedLimiter("test-key", 1, 1)
+	b := sharedLimiter("test-key", 1, 1)
+	if a != b {
+		t.Fatal("expected sharedLimiter to return the same limiter for the same key")
+	}
+
+	other := sharedLimiter("other-key", 1, 1)
+	if a == other {
+		t.Fatal("expected sharedLimiter to return distinct limiters for distinct keys")
+	}
+}
+
+func TestNewSMSNotifierSharesLimiterPerAccount(t *testing.T) {
+	a := NewSMSNotifier("AC123", "token", "+1555", "+1666")
+	b := NewSMSNotifier("AC123", "token", "+1555", "+1777")
+	if a.limiter != b.limiter {
+		t.Fatal("expected two SMSNotifiers for the same Twilio account to share one limiter")
+	}
+}
+
+func TestMultiNotifierAggregatesErrors(t *testing.T) {
+	a := &fakeNotifier{channel: "email"}
+	b := &fakeNotifier{channel: "sms", err: errors.New("twilio down")}
+	multi := NewMultiNotifier(a, b)
+
+	err := multi.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345"})
+	if err == nil {
+		t.Fatal("expected an error when a backend fails")
+	}
+	if a.calls != 1 || b.calls != 1 {
+		t.Fatalf("expected both backends to be attempted, got a=%d b=%d", a.calls, b.calls)
+	}
+}
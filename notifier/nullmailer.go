@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// NullMailer logs seat events instead of sending real email. It implements
+// the same "email" channel as SMTPNotifier so callers can swap one for the
+// other without touching any code that builds a Notifier slice. It's
+// selected automatically in dry-run mode, when no SMTP host is configured,
+// and is handy in tests that shouldn't need a live mail relay.
+type NullMailer struct{}
+
+// NewNullMailer builds a NullMailer.
+func NewNullMailer() *NullMailer {
+	return &NullMailer{}
+}
+
+func (n *NullMailer) Channel() string { return "email" }
+
+func (n *NullMailer) Notify(ctx context.Context, event SeatEvent) error {
+	log.Printf("nullmailer: would email %s: %d seats available for %s", event.Email, event.AvailableSeats, event.CRN)
+	return nil
+}
+
+// SendRaw logs an arbitrary subject/body instead of sending it, matching
+// SMTPNotifier.SendRaw so main can call either through the same interface.
+func (n *NullMailer) SendRaw(ctx context.Context, to, subject, body string) error {
+	log.Printf("nullmailer: would send %q to %s", subject, to)
+	return nil
+}
+
+var _ Notifier = (*NullMailer)(nil)
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// perChannelLimiter enforces a shared rate limit across all sends made
+// through it, so one chatty subscription can't exhaust a provider's quota
+// (e.g. Twilio) for everyone else.
+type perChannelLimiter struct {
+	limiter *rate.Limiter
+}
+
+// newPerChannelLimiter allows burst sends immediately and then refills at
+// ratePerSecond.
+func newPerChannelLimiter(ratePerSecond float64, burst int) *perChannelLimiter {
+	return &perChannelLimiter{limiter: rate.NewLimiter(rate.Limit(ratePerSecond), burst)}
+}
+
+func (l *perChannelLimiter) wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
+
+// limiters maps a provider-quota key (e.g. a Twilio account SID, a webhook
+// URL) to the one perChannelLimiter shared by every backend instance built
+// against it. Without this, notifiersFromConfigs building a fresh
+// SMSNotifier/WebhookNotifier/MastodonNotifier per subscription would hand
+// each one its own untouched token bucket, and the "shared quota" rate
+// limit would never actually throttle traffic across subscribers.
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*perChannelLimiter)
+)
+
+// sharedLimiter returns the perChannelLimiter registered for key, creating
+// one with the given rate/burst the first time key is seen.
+func sharedLimiter(key string, ratePerSecond float64, burst int) *perChannelLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l, ok := limiters[key]; ok {
+		return l
+	}
+	l := newPerChannelLimiter(ratePerSecond, burst)
+	limiters[key] = l
+	return l
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SMSNotifier sends a text message through the Twilio Messages API.
+type SMSNotifier struct {
+	AccountSID string
+	AuthToken  string
+	FromNumber string
+	ToNumber   string
+	Client     *http.Client
+
+	limiter *perChannelLimiter
+}
+
+// NewSMSNotifier builds an SMSNotifier rate-limited to 1 send/second across
+// every SMSNotifier built for the same Twilio account, which keeps the
+// account well under Twilio's per-account throughput cap no matter how many
+// subscribers share it.
+func NewSMSNotifier(accountSID, authToken, fromNumber, toNumber string) *SMSNotifier {
+	return &SMSNotifier{
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		FromNumber: fromNumber,
+		ToNumber:   toNumber,
+		Client:     &http.Client{Timeout: defaultHTTPTimeout},
+		limiter:    sharedLimiter("sms:"+accountSID, 1, 3),
+	}
+}
+
+func (s *SMSNotifier) Channel() string { return "sms" }
+
+func (s *SMSNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	if err := s.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", s.AccountSID)
+	body := fmt.Sprintf("A slot is available. There are %d seats available for you subscribe course: %s",
+		event.AvailableSeats, event.CRN)
+	if event.UnsubscribeLink != "" {
+		body += " Unsubscribe: " + event.UnsubscribeLink
+	}
+
+	form := url.Values{}
+	form.Set("From", s.FromNumber)
+	form.Set("To", s.ToNumber)
+	form.Set("Body", body)
+
+	return withRetry(ctx, defaultRetry, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(s.AccountSID, s.AuthToken)
+
+		resp, err := s.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("twilio: unexpected status %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+var _ Notifier = (*SMSNotifier)(nil)
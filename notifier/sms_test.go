@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// redirectingTransport rewrites every request's scheme/host to target, so a
+// backend that hardcodes a provider's real API URL (like SMSNotifier's
+// Twilio endpoint) can still be pointed at an httptest.Server in tests.
+type redirectingTransport struct {
+	target *url.URL
+}
+
+func (rt redirectingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSMSNotifierPostsTwilioFormPayload(t *testing.T) {
+	var gotContentType, gotUser, gotPass string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotUser, gotPass, _ = r.BasicAuth()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	s := NewSMSNotifier("AC123", "authtoken", "+1555", "+1666")
+	s.Client = &http.Client{Transport: redirectingTransport{target: target}}
+
+	err := s.Notify(context.Background(), SeatEvent{
+		Email:           "a@example.com",
+		CRN:             "12345",
+		AvailableSeats:  3,
+		UnsubscribeLink: "https://example.com/unsubscribe?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if gotContentType != "application/x-www-form-urlencoded" {
+		t.Fatalf("Content-Type = %q, want application/x-www-form-urlencoded", gotContentType)
+	}
+	if gotUser != "AC123" || gotPass != "authtoken" {
+		t.Fatalf("basic auth = %q/%q, want the account SID and auth token", gotUser, gotPass)
+	}
+
+	form, err := url.ParseQuery(string(gotBody))
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("From") != "+1555" || form.Get("To") != "+1666" {
+		t.Fatalf("From/To = %q/%q, want +1555/+1666", form.Get("From"), form.Get("To"))
+	}
+	if !strings.Contains(form.Get("Body"), "12345") {
+		t.Fatalf("Body = %q, want it to mention the CRN", form.Get("Body"))
+	}
+	if !strings.Contains(form.Get("Body"), "https://example.com/unsubscribe?token=abc") {
+		t.Fatalf("Body = %q, want it to include the unsubscribe link", form.Get("Body"))
+	}
+}
+
+func TestSMSNotifierRetriesOnFailureThenSucceeds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	s := NewSMSNotifier("AC456", "authtoken", "+1555", "+1666")
+	s.Client = &http.Client{Transport: redirectingTransport{target: target}}
+
+	if err := s.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345", AvailableSeats: 1}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("server called %d times, want 2 (one failure then a retry that succeeds)", got)
+	}
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPNotifier sends seat-availability emails through a plain SMTP relay
+// (e.g. Gmail's smtp.gmail.com:587). Username is usually the same address
+// as From, but some relays authenticate a shared mailbox under a different
+// account than the one mail appears to come from.
+type SMTPNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	From     string
+	Password string
+
+	limiter *perChannelLimiter
+}
+
+// NewSMTPNotifier builds an SMTPNotifier rate-limited to one send/second
+// with a burst of 5, which is comfortably under Gmail's relay limits.
+func NewSMTPNotifier(host, port, username, from, password string) *SMTPNotifier {
+	return &SMTPNotifier{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		From:     from,
+		Password: password,
+		limiter:  newPerChannelLimiter(1, 5),
+	}
+}
+
+func (s *SMTPNotifier) Channel() string { return "email" }
+
+func (s *SMTPNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	body := "A slot is available. There are " + strconv.Itoa(event.AvailableSeats) +
+		" seats available for you subscribe course: " + event.CRN
+	if event.UnsubscribeLink != "" {
+		body += "\r\n\r\nNo longer interested? Unsubscribe: " + event.UnsubscribeLink
+	}
+	return s.SendRaw(ctx, event.Email, "Course Slot Available", body)
+}
+
+// SendRaw sends an arbitrary subject/body email to "to", subject to the same
+// rate limit and retry policy as seat-event notifications. It's also used
+// for transactional mail (confirmation links) outside of Notify.
+func (s *SMTPNotifier) SendRaw(ctx context.Context, to, subject, body string) error {
+	if err := s.limiter.wait(ctx); err != nil {
+		return err
+	}
+	auth := smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	message := []byte("Subject: " + subject + "\r\n\r\n" + body)
+
+	return withRetry(ctx, defaultRetry, func() error {
+		return smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{to}, message)
+	})
+}
+
+var _ Notifier = (*SMTPNotifier)(nil)
+
+func (s *SMTPNotifier) String() string {
+	return fmt.Sprintf("smtp(%s)", s.Host)
+}
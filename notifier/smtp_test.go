@@ -0,0 +1,147 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer speaks just enough of RFC 5321 plus AUTH PLAIN to satisfy
+// net/smtp.SendMail, so SMTPNotifier can be exercised without a real relay.
+// failFirstN connections are accepted and then dropped without a greeting,
+// simulating the transient failures withRetry is meant to recover from.
+type fakeSMTPServer struct {
+	ln         net.Listener
+	failFirstN int
+	attempts   int
+
+	mailFrom string
+	rcptTo   string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T, failFirstN int) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{ln: ln, failFirstN: failFirstN}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *fakeSMTPServer) close() { s.ln.Close() }
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.attempts++
+		if s.attempts <= s.failFirstN {
+			conn.Close()
+			continue
+		}
+		s.handle(conn)
+		return
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "220 fake.smtp ESMTP\r\n")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			fmt.Fprintf(conn, "250-fake.smtp greets you\r\n250 AUTH PLAIN\r\n")
+		case strings.HasPrefix(upper, "AUTH PLAIN"):
+			fmt.Fprintf(conn, "235 2.7.0 Authentication successful\r\n")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mailFrom = line[len("MAIL FROM:"):]
+			fmt.Fprintf(conn, "250 2.1.0 Ok\r\n")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcptTo = line[len("RCPT TO:"):]
+			fmt.Fprintf(conn, "250 2.1.5 Ok\r\n")
+		case upper == "DATA":
+			fmt.Fprintf(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+			var body strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if strings.TrimRight(dataLine, "\r\n") == "." {
+					break
+				}
+				body.WriteString(dataLine)
+			}
+			s.data = body.String()
+			fmt.Fprintf(conn, "250 2.0.0 Ok: queued\r\n")
+		case upper == "QUIT":
+			fmt.Fprintf(conn, "221 2.0.0 Bye\r\n")
+			return
+		default:
+			fmt.Fprintf(conn, "500 5.5.2 Unrecognized command\r\n")
+		}
+	}
+}
+
+func splitHostPort(addr string) (string, string) {
+	i := strings.LastIndex(addr, ":")
+	return addr[:i], addr[i+1:]
+}
+
+func TestSMTPNotifierSendRawDeliversMessage(t *testing.T) {
+	srv := newFakeSMTPServer(t, 0)
+	defer srv.close()
+	host, port := splitHostPort(srv.addr())
+
+	s := NewSMTPNotifier(host, port, "user", "from@example.com", "password")
+	if err := s.SendRaw(context.Background(), "to@example.com", "Confirm your check", "visit this link"); err != nil {
+		t.Fatalf("SendRaw: %v", err)
+	}
+
+	if !strings.Contains(srv.mailFrom, "from@example.com") {
+		t.Fatalf("MAIL FROM = %q, want it to contain from@example.com", srv.mailFrom)
+	}
+	if !strings.Contains(srv.rcptTo, "to@example.com") {
+		t.Fatalf("RCPT TO = %q, want it to contain to@example.com", srv.rcptTo)
+	}
+	if !strings.Contains(srv.data, "Subject: Confirm your check") {
+		t.Fatalf("message = %q, want a Subject header", srv.data)
+	}
+	if !strings.Contains(srv.data, "visit this link") {
+		t.Fatalf("message = %q, want the body", srv.data)
+	}
+}
+
+func TestSMTPNotifierRetriesOnFailureThenSucceeds(t *testing.T) {
+	srv := newFakeSMTPServer(t, 1)
+	defer srv.close()
+	host, port := splitHostPort(srv.addr())
+
+	s := NewSMTPNotifier(host, port, "user", "from@example.com", "password")
+	if err := s.SendRaw(context.Background(), "to@example.com", "Subject", "body"); err != nil {
+		t.Fatalf("SendRaw: %v", err)
+	}
+	if srv.attempts != 2 {
+		t.Fatalf("server accepted %d connections, want 2 (one dropped, one successful retry)", srv.attempts)
+	}
+}
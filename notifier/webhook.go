@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookNotifier POSTs a Discord/Slack incoming-webhook compatible JSON
+// payload ({"content": "..."}) to an arbitrary URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+
+	limiter *perChannelLimiter
+}
+
+// NewWebhookNotifier builds a WebhookNotifier rate-limited to 2 sends/second
+// across every WebhookNotifier built for the same URL, so many subscribers
+// pointed at one webhook don't multiply the effective rate against it.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:     url,
+		Client:  &http.Client{Timeout: defaultHTTPTimeout},
+		limiter: sharedLimiter("webhook:"+url, 2, 5),
+	}
+}
+
+func (w *WebhookNotifier) Channel() string { return "webhook" }
+
+func (w *WebhookNotifier) Notify(ctx context.Context, event SeatEvent) error {
+	if err := w.limiter.wait(ctx); err != nil {
+		return err
+	}
+	content := fmt.Sprintf("Seat available for %s: %d seats open for %s", event.Email, event.AvailableSeats, event.CRN)
+	if event.UnsubscribeLink != "" {
+		content += fmt.Sprintf(" (unsubscribe: %s)", event.UnsubscribeLink)
+	}
+	payload, err := json.Marshal(struct {
+		Content string `json:"content"`
+	}{
+		Content: content,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: marshal payload: %w", err)
+	}
+
+	return withRetry(ctx, defaultRetry, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := w.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
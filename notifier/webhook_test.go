@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWebhookNotifierPostsContentPayload(t *testing.T) {
+	var gotBody []byte
+	var gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	w := NewWebhookNotifier(srv.URL)
+	err := w.Notify(context.Background(), SeatEvent{
+		Email:           "a@example.com",
+		CRN:             "12345",
+		AvailableSeats:  2,
+		UnsubscribeLink: "https://example.com/unsubscribe?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if gotContentType != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", gotContentType)
+	}
+
+	var payload struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if !strings.Contains(payload.Content, "12345") || !strings.Contains(payload.Content, "2 seats") {
+		t.Fatalf("content = %q, want it to mention the CRN and seat count", payload.Content)
+	}
+	if !strings.Contains(payload.Content, "https://example.com/unsubscribe?token=abc") {
+		t.Fatalf("content = %q, want it to include the unsubscribe link", payload.Content)
+	}
+}
+
+func TestWebhookNotifierRetriesOnFailureThenSucceeds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	w := NewWebhookNotifier(srv.URL)
+	if err := w.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345", AvailableSeats: 1}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("server called %d times, want 2 (one failure then a retry that succeeds)", got)
+	}
+}
+
+func TestWebhookNotifierFailsAfterExhaustingRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w := NewWebhookNotifier(srv.URL)
+	err := w.Notify(context.Background(), SeatEvent{Email: "a@example.com", CRN: "12345", AvailableSeats: 1})
+	if err == nil {
+		t.Fatal("expected an error once every retry is exhausted")
+	}
+	if got := atomic.LoadInt32(&calls); got != int32(defaultRetry.maxAttempts) {
+		t.Fatalf("server called %d times, want %d (defaultRetry.maxAttempts)", got, defaultRetry.maxAttempts)
+	}
+}
@@ -0,0 +1,50 @@
+package poller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metrics are the Prometheus counters exposed on /metrics.
+type metrics struct {
+	polls         prometheus.Counter
+	hits          prometheus.Counter
+	backoffs      prometheus.Counter
+	notifications prometheus.Counter
+}
+
+var (
+	metricsOnce   sync.Once
+	sharedMetrics *metrics
+)
+
+// newMetrics returns the package's Prometheus counters, registering them
+// with the default registry exactly once. The counters are process-global
+// regardless of how many Pollers exist, so every New() call (including
+// repeated calls from tests in the same process) shares one set instead of
+// panicking on duplicate registration.
+func newMetrics() *metrics {
+	metricsOnce.Do(func() {
+		sharedMetrics = &metrics{
+			polls: promauto.NewCounter(prometheus.CounterOpts{
+				Name: "nubanner_polls_total",
+				Help: "Total number of Banner enrollment-info requests made.",
+			}),
+			hits: promauto.NewCounter(prometheus.CounterOpts{
+				Name: "nubanner_hits_total",
+				Help: "Total number of polls that observed at least one available seat.",
+			}),
+			backoffs: promauto.NewCounter(prometheus.CounterOpts{
+				Name: "nubanner_backoffs_total",
+				Help: "Total number of times a CRN's poll interval was backed off due to an error.",
+			}),
+			notifications: promauto.NewCounter(prometheus.CounterOpts{
+				Name: "nubanner_notifications_total",
+				Help: "Total number of notifications sent to subscribers.",
+			}),
+		}
+	})
+	return sharedMetrics
+}
@@ -0,0 +1,272 @@
+// Package poller centralizes Banner polling behind one goroutine per CRN,
+// so N users watching the same section share one HTTP call instead of
+// spawning N independent pollers that would get the service rate-limited.
+package poller
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Jianzhong-Liu/NuBanner/banner"
+	"github.com/Jianzhong-Liu/NuBanner/notifier"
+	"github.com/Jianzhong-Liu/NuBanner/store"
+)
+
+const (
+	baseInterval = 60 * time.Second
+	jitterWindow = 15 * time.Second
+	cacheTTL     = 30 * time.Second
+	maxBackoff   = 10 * time.Minute
+)
+
+// subscriber pairs a persisted subscription with its notification backend.
+type subscriber struct {
+	sub      store.Subscription
+	notifier notifier.Notifier
+}
+
+// crnState is everything the Poller tracks for a single CRN: who's
+// watching it, the last seat count observed, and how far its poll interval
+// has backed off after errors.
+type crnState struct {
+	mu          sync.Mutex
+	subscribers []subscriber
+	cachedSeats int
+	cachedAt    time.Time
+	backoff     time.Duration
+	stopChan    chan struct{}
+}
+
+func (s *crnState) snapshot() []subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := make([]subscriber, len(s.subscribers))
+	copy(subs, s.subscribers)
+	return subs
+}
+
+func (s *crnState) cached() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cachedAt.IsZero() || time.Since(s.cachedAt) > cacheTTL {
+		return 0, false
+	}
+	return s.cachedSeats, true
+}
+
+func (s *crnState) setCache(seats int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cachedSeats = seats
+	s.cachedAt = time.Now()
+}
+
+func (s *crnState) currentBackoff() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backoff
+}
+
+func (s *crnState) backOff() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backoff == 0 {
+		s.backoff = baseInterval
+	} else {
+		s.backoff *= 2
+	}
+	if s.backoff > maxBackoff {
+		s.backoff = maxBackoff
+	}
+}
+
+func (s *crnState) resetBackoff() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backoff = 0
+}
+
+// seatFetcher is the subset of *banner.Client the Poller needs. It's kept
+// as an interface, like store.Store, so tests can swap in a fake instead of
+// hitting Banner over the network.
+type seatFetcher interface {
+	GetEnrollmentInfo(ctx context.Context, crn string) (banner.EnrollmentInfo, error)
+}
+
+// Poller polls every distinct CRN it's asked to Watch on an independent,
+// jittered schedule and fans each result out to that CRN's subscribers.
+type Poller struct {
+	store    store.Store
+	banner   seatFetcher
+	interval time.Duration
+	metrics  *metrics
+
+	// LinkFunc builds the unsubscribe link included in notifications. It's
+	// injected rather than imported so poller doesn't need to know about
+	// the server's token secret or base URL.
+	LinkFunc func(email, crn string) string
+
+	mu   sync.Mutex
+	crns map[string]*crnState
+}
+
+// New builds a Poller that polls Banner through bannerClient on the given
+// base interval (±jitter, before backoff), recording poll results and
+// notifications through s. An interval <= 0 falls back to baseInterval.
+func New(s store.Store, bannerClient seatFetcher, interval time.Duration) *Poller {
+	if interval <= 0 {
+		interval = baseInterval
+	}
+	return &Poller{
+		store:    s,
+		banner:   bannerClient,
+		interval: interval,
+		metrics:  newMetrics(),
+		crns:     make(map[string]*crnState),
+	}
+}
+
+// Watch adds sub to its CRN's subscriber list, starting a poller goroutine
+// for that CRN if it isn't already being watched by someone else. It holds
+// p.mu for its entire body (not just the map lookup) so it can't interleave
+// with Unwatch deleting the same CRN's state out from under it.
+func (p *Poller) Watch(sub store.Subscription, n notifier.Notifier) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, exists := p.crns[sub.CRN]
+	if !exists {
+		state = &crnState{stopChan: make(chan struct{})}
+		p.crns[sub.CRN] = state
+	}
+
+	state.mu.Lock()
+	state.subscribers = append(state.subscribers, subscriber{sub: sub, notifier: n})
+	state.mu.Unlock()
+
+	if !exists {
+		go p.run(sub.CRN, state)
+	}
+}
+
+// Unwatch removes email's subscription to crn, stopping that CRN's poller
+// goroutine once nobody is watching it anymore. The subscriber-list mutation
+// and the emptiness check that decides whether to delete/stop run under
+// p.mu (not just state.mu), so a concurrent Watch for the same CRN can't
+// append a subscriber to a state this call is about to discard.
+func (p *Poller) Unwatch(email, crn string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, exists := p.crns[crn]
+	if !exists {
+		return
+	}
+
+	state.mu.Lock()
+	remaining := make([]subscriber, 0, len(state.subscribers))
+	for _, sub := range state.subscribers {
+		if sub.sub.Email != email {
+			remaining = append(remaining, sub)
+		}
+	}
+	state.subscribers = remaining
+	empty := len(remaining) == 0
+	state.mu.Unlock()
+
+	if !empty {
+		return
+	}
+
+	delete(p.crns, crn)
+	close(state.stopChan)
+}
+
+func (p *Poller) run(crn string, state *crnState) {
+	for {
+		select {
+		case <-time.After(p.jittered(state.currentBackoff())):
+			p.poll(crn, state)
+		case <-state.stopChan:
+			return
+		}
+	}
+}
+
+// jittered spreads the Poller's base interval by ±jitterWindow so every
+// watched CRN doesn't poll Banner in lockstep, plus whatever backoff the
+// CRN has accumulated from recent errors.
+func (p *Poller) jittered(backoff time.Duration) time.Duration {
+	spread := time.Duration(rand.Int63n(int64(2*jitterWindow))) - jitterWindow
+	return p.interval + backoff + spread
+}
+
+func (p *Poller) poll(crn string, state *crnState) {
+	subs := state.snapshot()
+	if len(subs) == 0 {
+		return
+	}
+
+	seats, err := p.seatsFor(crn, state)
+	if err != nil {
+		log.Println("poller: fetching", crn, ":", err)
+		state.backOff()
+		p.metrics.backoffs.Inc()
+		return
+	}
+	state.resetBackoff()
+
+	now := time.Now()
+	for _, sub := range subs {
+		if err := p.store.UpdateLastPoll(sub.sub.ID, seats, now); err != nil {
+			log.Println("poller: update last poll:", err)
+		}
+	}
+
+	if seats <= 0 {
+		return
+	}
+	p.metrics.hits.Inc()
+	p.notifySubscribers(crn, subs, seats)
+}
+
+// seatsFor returns the current seat count for crn, serving a cached value
+// within cacheTTL so duplicate watchers share one Banner request.
+func (p *Poller) seatsFor(crn string, state *crnState) (int, error) {
+	if seats, ok := state.cached(); ok {
+		return seats, nil
+	}
+	p.metrics.polls.Inc()
+	info, err := p.banner.GetEnrollmentInfo(context.Background(), crn)
+	if err != nil {
+		return 0, err
+	}
+	state.setCache(info.Seats)
+	return info.Seats, nil
+}
+
+func (p *Poller) notifySubscribers(crn string, subs []subscriber, seats int) {
+	for _, sub := range subs {
+		var link string
+		if p.LinkFunc != nil {
+			link = p.LinkFunc(sub.sub.Email, crn)
+		}
+		event := notifier.SeatEvent{
+			Email:           sub.sub.Email,
+			CRN:             crn,
+			AvailableSeats:  seats,
+			UnsubscribeLink: link,
+		}
+		if err := sub.notifier.Notify(context.Background(), event); err != nil {
+			log.Println("poller: notify", sub.sub.Email, crn, ":", err)
+			continue
+		}
+		p.metrics.notifications.Inc()
+		if err := p.store.RecordNotification(sub.sub.ID, seats, time.Now()); err != nil {
+			log.Println("poller: record notification:", err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jianzhong-Liu/NuBanner/banner"
+	"github.com/Jianzhong-Liu/NuBanner/notifier"
+	"github.com/Jianzhong-Liu/NuBanner/store"
+)
+
+// fakeFetcher is a seatFetcher that returns a fixed seat count or error
+// instead of hitting Banner, and counts how many times it was called.
+type fakeFetcher struct {
+	mu    sync.Mutex
+	seats int
+	err   error
+	calls int
+}
+
+func (f *fakeFetcher) GetEnrollmentInfo(ctx context.Context, crn string) (banner.EnrollmentInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return banner.EnrollmentInfo{}, f.err
+	}
+	return banner.EnrollmentInfo{Seats: f.seats}, nil
+}
+
+func (f *fakeFetcher) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// fakeStore implements store.Store by embedding a nil Store and overriding
+// only the methods poller actually calls; any other method panics.
+type fakeStore struct {
+	store.Store
+}
+
+func (f *fakeStore) UpdateLastPoll(id int64, seats int, at time.Time) error { return nil }
+func (f *fakeStore) RecordNotification(id int64, seats int, at time.Time) error { return nil }
+
+type fakeNotifier struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (n *fakeNotifier) Channel() string { return "fake" }
+
+func (n *fakeNotifier) Notify(ctx context.Context, event notifier.SeatEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls++
+	return nil
+}
+
+func TestSeatsForCachesWithinTTL(t *testing.T) {
+	fetcher := &fakeFetcher{seats: 3}
+	p := New(&fakeStore{}, fetcher, time.Minute)
+	state := &crnState{stopChan: make(chan struct{})}
+
+	for i := 0; i < 3; i++ {
+		seats, err := p.seatsFor("12345", state)
+		if err != nil {
+			t.Fatalf("seatsFor: %v", err)
+		}
+		if seats != 3 {
+			t.Fatalf("seats = %d, want 3", seats)
+		}
+	}
+	if got := fetcher.callCount(); got != 1 {
+		t.Fatalf("fetcher called %d times, want 1 (cached)", got)
+	}
+}
+
+func TestPollBacksOffOnFetchError(t *testing.T) {
+	fetcher := &fakeFetcher{err: errors.New("banner unavailable")}
+	p := New(&fakeStore{}, fetcher, time.Minute)
+	state := &crnState{stopChan: make(chan struct{})}
+	n := &fakeNotifier{}
+	state.subscribers = []subscriber{{sub: store.Subscription{ID: 1, Email: "a@example.com", CRN: "12345"}, notifier: n}}
+
+	p.poll("12345", state)
+	if state.currentBackoff() != baseInterval {
+		t.Fatalf("backoff = %v, want %v after one failure", state.currentBackoff(), baseInterval)
+	}
+
+	p.poll("12345", state)
+	if state.currentBackoff() != 2*baseInterval {
+		t.Fatalf("backoff = %v, want %v after two failures", state.currentBackoff(), 2*baseInterval)
+	}
+	if n.calls != 0 {
+		t.Fatalf("notifier called %d times, want 0 on fetch error", n.calls)
+	}
+}
+
+func TestPollResetsBackoffAndNotifiesOnOpenSeat(t *testing.T) {
+	fetcher := &fakeFetcher{seats: 2}
+	p := New(&fakeStore{}, fetcher, time.Minute)
+	state := &crnState{stopChan: make(chan struct{}), backoff: baseInterval}
+	n := &fakeNotifier{}
+	state.subscribers = []subscriber{{sub: store.Subscription{ID: 1, Email: "a@example.com", CRN: "12345"}, notifier: n}}
+
+	p.poll("12345", state)
+
+	if state.currentBackoff() != 0 {
+		t.Fatalf("backoff = %v, want 0 after a successful poll", state.currentBackoff())
+	}
+	if n.calls != 1 {
+		t.Fatalf("notifier called %d times, want 1", n.calls)
+	}
+}
+
+// TestConcurrentWatchUnwatchLeavesConsistentState guards against the race
+// where Unwatch reads a CRN's subscriber list as empty, and before it
+// deletes the crnState and closes its stopChan, a concurrent Watch for the
+// same CRN appends a subscriber to that same about-to-be-discarded state,
+// orphaning it silently. Watch/Unwatch now hold p.mu for their whole body,
+// so whatever survives this race must stay internally consistent: if a
+// CRN's state is still in the map, it must have a live, reachable
+// subscriber list.
+func TestConcurrentWatchUnwatchLeavesConsistentState(t *testing.T) {
+	p := New(&fakeStore{}, &fakeFetcher{seats: 0}, time.Hour)
+	const crn = "12345"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.Watch(store.Subscription{Email: email, CRN: crn}, &fakeNotifier{})
+		}()
+		go func() {
+			defer wg.Done()
+			p.Unwatch(email, crn)
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	state, exists := p.crns[crn]
+	p.mu.Unlock()
+	if !exists {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if len(state.subscribers) == 0 {
+		t.Fatal("crn left registered in the map with zero subscribers and a goroutine with nothing to serve")
+	}
+}
@@ -0,0 +1,69 @@
+package store
+
+// migrations are applied in order against a fresh or existing database. Each
+// entry is recorded once in the schema_migrations table and not applied again
+// after that, except the first, which creates that table idempotently and is
+// run on every migrate call.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`,
+
+	`CREATE TABLE IF NOT EXISTS subscriptions (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		email      TEXT NOT NULL,
+		crn        TEXT NOT NULL,
+		channels   TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME NOT NULL,
+		UNIQUE(email, crn)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id              INTEGER PRIMARY KEY AUTOINCREMENT,
+		subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+		seats           INTEGER NOT NULL,
+		sent_at         DATETIME NOT NULL
+	)`,
+
+	`ALTER TABLE subscriptions ADD COLUMN pending INTEGER NOT NULL DEFAULT 0`,
+
+	`CREATE TABLE IF NOT EXISTS used_tokens (
+		nonce   TEXT PRIMARY KEY,
+		used_at DATETIME NOT NULL
+	)`,
+
+	`ALTER TABLE subscriptions ADD COLUMN last_seats INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE subscriptions ADD COLUMN last_polled_at DATETIME`,
+}
+
+func migrate(db execer) error {
+	if _, err := db.Exec(migrations[0]); err != nil {
+		return err
+	}
+
+	applied := map[int]bool{}
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+
+	for version, stmt := range migrations {
+		if applied[version] {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`INSERT INTO schema_migrations(version) VALUES (?)`, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
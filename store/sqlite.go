@@ -0,0 +1,165 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // CGO-free sqlite driver, registers "sqlite"
+)
+
+// execer is the subset of *sql.DB migrate needs, so it can be exercised
+// against a real database in tests without mocking the driver.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLiteStore is a Store backed by a SQLite database file.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at path
+// and brings its schema up to date.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: migrate %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Add(sub Subscription) error {
+	return s.insert(sub, false)
+}
+
+func (s *SQLiteStore) AddPending(sub Subscription) error {
+	return s.insert(sub, true)
+}
+
+func (s *SQLiteStore) insert(sub Subscription, pending bool) error {
+	channels, err := json.Marshal(sub.Channels)
+	if err != nil {
+		return fmt.Errorf("store: marshal channels: %w", err)
+	}
+	createdAt := sub.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO subscriptions(email, crn, channels, pending, created_at) VALUES (?, ?, ?, ?, ?)`,
+		sub.Email, sub.CRN, string(channels), pending, createdAt,
+	)
+	if err != nil {
+		return fmt.Errorf("store: add subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Confirm(email, crn string) error {
+	res, err := s.db.Exec(
+		`UPDATE subscriptions SET pending = 0 WHERE email = ? AND crn = ? AND pending = 1`,
+		email, crn,
+	)
+	if err != nil {
+		return fmt.Errorf("store: confirm subscription: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("store: confirm subscription: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("store: no pending subscription for %s/%s", email, crn)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Remove(email, crn string) error {
+	if _, err := s.db.Exec(`DELETE FROM subscriptions WHERE email = ? AND crn = ?`, email, crn); err != nil {
+		return fmt.Errorf("store: remove subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) List() ([]Subscription, error) {
+	return s.query(`SELECT id, email, crn, channels, pending, last_seats, last_polled_at, created_at
+		FROM subscriptions WHERE pending = 0`)
+}
+
+func (s *SQLiteStore) ListByEmail(email string) ([]Subscription, error) {
+	return s.query(`SELECT id, email, crn, channels, pending, last_seats, last_polled_at, created_at
+		FROM subscriptions WHERE pending = 0 AND email = ?`, email)
+}
+
+func (s *SQLiteStore) query(query string, args ...interface{}) ([]Subscription, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var channels string
+		var lastPolledAt sql.NullTime
+		if err := rows.Scan(&sub.ID, &sub.Email, &sub.CRN, &channels, &sub.Pending, &sub.LastSeats, &lastPolledAt, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("store: scan subscription: %w", err)
+		}
+		if err := json.Unmarshal([]byte(channels), &sub.Channels); err != nil {
+			return nil, fmt.Errorf("store: unmarshal channels: %w", err)
+		}
+		if lastPolledAt.Valid {
+			sub.LastPolledAt = lastPolledAt.Time
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *SQLiteStore) UpdateLastPoll(id int64, seats int, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE subscriptions SET last_seats = ?, last_polled_at = ? WHERE id = ?`, seats, at, id)
+	if err != nil {
+		return fmt.Errorf("store: update last poll: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) RecordNotification(id int64, seats int, at time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO notifications(subscription_id, seats, sent_at) VALUES (?, ?, ?)`,
+		id, seats, at,
+	)
+	if err != nil {
+		return fmt.Errorf("store: record notification: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) IsTokenUsed(nonce string) (bool, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(1) FROM used_tokens WHERE nonce = ?`, nonce).Scan(&count); err != nil {
+		return false, fmt.Errorf("store: check token: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (s *SQLiteStore) MarkTokenUsed(nonce string) error {
+	_, err := s.db.Exec(`INSERT INTO used_tokens(nonce, used_at) VALUES (?, ?)`, nonce, time.Now())
+	if err != nil {
+		return fmt.Errorf("store: mark token used: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+var _ Store = (*SQLiteStore)(nil)
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "nubanner.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestAddAndList(t *testing.T) {
+	s := newTestStore(t)
+
+	sub := Subscription{
+		Email:    "student@northeastern.edu",
+		CRN:      "12345",
+		Channels: []ChannelConfig{{Type: "email"}},
+	}
+	if err := s.Add(sub); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	subs, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(subs) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(subs))
+	}
+	got := subs[0]
+	if got.Email != sub.Email || got.CRN != sub.CRN {
+		t.Fatalf("unexpected subscription: %+v", got)
+	}
+	if len(got.Channels) != 1 || got.Channels[0].Type != "email" {
+		t.Fatalf("unexpected channels: %+v", got.Channels)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	s := newTestStore(t)
+
+	sub := Subscription{Email: "student@northeastern.edu", CRN: "12345"}
+	if err := s.Add(sub); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Remove(sub.Email, sub.CRN); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	subs, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(subs) != 0 {
+		t.Fatalf("expected subscription to be removed, got %d remaining", len(subs))
+	}
+}
+
+func TestRecordNotification(t *testing.T) {
+	s := newTestStore(t)
+
+	sub := Subscription{Email: "student@northeastern.edu", CRN: "12345"}
+	if err := s.Add(sub); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	subs, err := s.List()
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("List: %v %v", subs, err)
+	}
+
+	if err := s.RecordNotification(subs[0].ID, 3, time.Now()); err != nil {
+		t.Fatalf("RecordNotification: %v", err)
+	}
+}
+
+func TestPendingSubscriptionRequiresConfirm(t *testing.T) {
+	s := newTestStore(t)
+
+	sub := Subscription{Email: "student@northeastern.edu", CRN: "12345"}
+	if err := s.AddPending(sub); err != nil {
+		t.Fatalf("AddPending: %v", err)
+	}
+
+	subs, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(subs) != 0 {
+		t.Fatalf("expected pending subscription to be hidden from List, got %d", len(subs))
+	}
+
+	if err := s.Confirm(sub.Email, sub.CRN); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	subs, err = s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(subs) != 1 {
+		t.Fatalf("expected confirmed subscription in List, got %d", len(subs))
+	}
+
+	if err := s.Confirm(sub.Email, sub.CRN); err == nil {
+		t.Fatal("expected confirming an already-confirmed subscription to fail")
+	}
+}
+
+func TestTokenSingleUse(t *testing.T) {
+	s := newTestStore(t)
+
+	used, err := s.IsTokenUsed("abc123")
+	if err != nil {
+		t.Fatalf("IsTokenUsed: %v", err)
+	}
+	if used {
+		t.Fatal("expected fresh nonce to be unused")
+	}
+
+	if err := s.MarkTokenUsed("abc123"); err != nil {
+		t.Fatalf("MarkTokenUsed: %v", err)
+	}
+
+	used, err = s.IsTokenUsed("abc123")
+	if err != nil {
+		t.Fatalf("IsTokenUsed: %v", err)
+	}
+	if !used {
+		t.Fatal("expected marked nonce to be reported as used")
+	}
+}
+
+func TestListByEmailAndUpdateLastPoll(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Add(Subscription{Email: "student@northeastern.edu", CRN: "111"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(Subscription{Email: "student@northeastern.edu", CRN: "222"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(Subscription{Email: "other@northeastern.edu", CRN: "333"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	subs, err := s.ListByEmail("student@northeastern.edu")
+	if err != nil {
+		t.Fatalf("ListByEmail: %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("expected 2 subscriptions for student, got %d", len(subs))
+	}
+
+	targetID := subs[0].ID
+	if err := s.UpdateLastPoll(targetID, 5, time.Now()); err != nil {
+		t.Fatalf("UpdateLastPoll: %v", err)
+	}
+
+	subs, err = s.ListByEmail("student@northeastern.edu")
+	if err != nil {
+		t.Fatalf("ListByEmail: %v", err)
+	}
+	var found bool
+	for _, sub := range subs {
+		if sub.ID == targetID && sub.LastSeats == 5 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected updated last seat count to be visible in ListByEmail")
+	}
+}
+
+func TestReopenAppliesMigrationsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nubanner.db")
+
+	s1, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	if err := s1.Add(Subscription{Email: "a@b.edu", CRN: "1"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	s1.Close()
+
+	s2, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("reopen NewSQLiteStore: %v", err)
+	}
+	defer s2.Close()
+
+	subs, err := s2.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(subs) != 1 {
+		t.Fatalf("expected persisted subscription to survive reopen, got %d", len(subs))
+	}
+}
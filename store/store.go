@@ -0,0 +1,66 @@
+// Package store persists subscriptions so active checks survive a server
+// restart.
+package store
+
+import "time"
+
+// ChannelConfig records one notification channel a subscription should fire
+// on, plus whatever destination the channel needs (a webhook URL, a phone
+// number, a Mastodon handle, ...). Email needs no Target since it's sent to
+// Subscription.Email.
+type ChannelConfig struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+}
+
+// Subscription is a single persisted watch: one email watching one CRN over
+// one or more channels. An email can have many Subscriptions, one per CRN
+// it watches.
+type Subscription struct {
+	ID           int64
+	Email        string
+	CRN          string
+	Channels     []ChannelConfig
+	Pending      bool
+	LastSeats    int
+	LastPolledAt time.Time
+	CreatedAt    time.Time
+}
+
+// Store is the persistence boundary the rest of the service programs
+// against, so the backing database can change without touching handlers.
+type Store interface {
+	// Add persists a new, already-confirmed subscription. Implementations
+	// should reject a duplicate (email, CRN) pair.
+	Add(sub Subscription) error
+	// AddPending persists a subscription awaiting confirmation via the
+	// double opt-in flow. It does not appear in List until Confirm is
+	// called for the same (email, CRN).
+	AddPending(sub Subscription) error
+	// Confirm activates a pending subscription. It returns an error if no
+	// matching pending subscription exists.
+	Confirm(email, crn string) error
+	// Remove deletes the subscription for (email, crn), pending or active.
+	// It is not an error to remove a subscription that doesn't exist.
+	Remove(email, crn string) error
+	// List returns every confirmed (non-pending) subscription, e.g. for
+	// respawning background checks on startup.
+	List() ([]Subscription, error)
+	// ListByEmail returns a single user's confirmed watchlist.
+	ListByEmail(email string) ([]Subscription, error)
+	// UpdateLastPoll records the seat count observed for a subscription on
+	// its most recent poll, so /subscriptions can report it without
+	// waiting on a notification.
+	UpdateLastPoll(id int64, seats int, at time.Time) error
+	// RecordNotification logs that a notification was sent for a
+	// subscription, for auditing and rate-limit bookkeeping.
+	RecordNotification(id int64, seats int, at time.Time) error
+	// IsTokenUsed reports whether a confirmation/unsubscribe token with the
+	// given nonce has already been redeemed.
+	IsTokenUsed(nonce string) (bool, error)
+	// MarkTokenUsed records a token's nonce as redeemed so it can't be
+	// replayed.
+	MarkTokenUsed(nonce string) error
+	// Close releases the underlying database handle.
+	Close() error
+}
@@ -0,0 +1,129 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-secret")
+
+func TestGenerateParseRoundTrip(t *testing.T) {
+	raw, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	claims, err := Parse(secret, raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if claims.Email != "a@example.com" || claims.CRN != "12345" || claims.Purpose != PurposeConfirm {
+		t.Fatalf("claims = %+v, want email/CRN/purpose to round-trip", claims)
+	}
+	if claims.Nonce == "" {
+		t.Fatal("claims.Nonce is empty, want a generated nonce")
+	}
+	if !claims.Expiry.After(time.Now()) {
+		t.Fatalf("claims.Expiry = %v, want it in the future", claims.Expiry)
+	}
+}
+
+func TestGenerateProducesDistinctNoncesForIdenticalInputs(t *testing.T) {
+	raw1, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	raw2, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	claims1, err := Parse(secret, raw1)
+	if err != nil {
+		t.Fatalf("Parse raw1: %v", err)
+	}
+	claims2, err := Parse(secret, raw2)
+	if err != nil {
+		t.Fatalf("Parse raw2: %v", err)
+	}
+	if claims1.Nonce == claims2.Nonce {
+		t.Fatal("expected two tokens for the same (email, crn, purpose) to carry distinct nonces")
+	}
+}
+
+func TestParseRejectsTamperedPayload(t *testing.T) {
+	raw, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	parts := strings.SplitN(raw, ".", 2)
+	tampered := parts[0] + "tampered." + parts[1]
+
+	if _, err := Parse(secret, tampered); err != ErrInvalidSignature {
+		t.Fatalf("Parse(tampered) err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParseRejectsWrongSecret(t *testing.T) {
+	raw, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if _, err := Parse([]byte("a different secret"), raw); err != ErrInvalidSignature {
+		t.Fatalf("Parse(wrong secret) err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParseRejectsExpiredToken(t *testing.T) {
+	raw, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, -time.Minute)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	claims, err := Parse(secret, raw)
+	if err != ErrExpired {
+		t.Fatalf("Parse(expired) err = %v, want ErrExpired", err)
+	}
+	if claims.Email != "a@example.com" {
+		t.Fatalf("claims.Email = %q, want the expired token's claims still returned", claims.Email)
+	}
+}
+
+func TestParseRejectsMalformedToken(t *testing.T) {
+	cases := []string{
+		"",
+		"no-dot-in-this-token",
+		"not-base64url!!.deadbeef",
+	}
+	for _, raw := range cases {
+		if _, err := Parse(secret, raw); err != ErrMalformed {
+			t.Errorf("Parse(%q) err = %v, want ErrMalformed", raw, err)
+		}
+	}
+}
+
+// TestClaimsPurposePreservedForMismatchDetection checks that Parse hands
+// back the Purpose a token was generated with unchanged, which is what lets
+// callers (see new_nu_check's validateToken) reject a confirm token replayed
+// against the unsubscribe endpoint, and vice versa. Parse itself doesn't
+// compare purposes; it just has to report the real one faithfully.
+func TestClaimsPurposePreservedForMismatchDetection(t *testing.T) {
+	raw, err := Generate(secret, "a@example.com", "12345", PurposeConfirm, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	claims, err := Parse(secret, raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if claims.Purpose == PurposeUnsubscribe {
+		t.Fatal("a confirm token parsed back with PurposeUnsubscribe, would let callers mistake it for an unsubscribe link")
+	}
+	if claims.Purpose != PurposeConfirm {
+		t.Fatalf("claims.Purpose = %q, want %q", claims.Purpose, PurposeConfirm)
+	}
+}